@@ -0,0 +1,39 @@
+package sharedtest
+
+import (
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/golang/mock/gomock"
+)
+
+// FakeAppServer wraps a MockAppServer with sensible defaults for the common
+// case of injecting a fake webdriver.AppServer into a test: GetWebappURL
+// resolves against an in-process httptest.Server, and Close tears that
+// server down. Callers can still set further expectations on the embedded
+// mock for anything test-specific.
+type FakeAppServer struct {
+	*MockAppServer
+
+	// Server is the httptest.Server backing GetWebappURL. Callers may
+	// replace its Config.Handler to customize responses.
+	Server *httptest.Server
+}
+
+// NewFakeAppServer creates a FakeAppServer backed by a httptest.Server that
+// 404s every request by default.
+func NewFakeAppServer(ctrl *gomock.Controller) *FakeAppServer {
+	server := httptest.NewServer(http.NotFoundHandler())
+	fake := &FakeAppServer{
+		MockAppServer: NewMockAppServer(ctrl),
+		Server:        server,
+	}
+	fake.EXPECT().GetWebappURL(gomock.Any()).DoAndReturn(func(path string) string {
+		return server.URL + path
+	}).AnyTimes()
+	fake.EXPECT().Close().DoAndReturn(func() error {
+		server.Close()
+		return nil
+	}).AnyTimes()
+	return fake
+}
@@ -0,0 +1,46 @@
+package sharedtest
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+)
+
+func TestFakeAppServer_GetWebappURL(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	fake := NewFakeAppServer(ctrl)
+	defer fake.Close()
+
+	got := fake.GetWebappURL("/results")
+	want := fake.Server.URL + "/results"
+	if got != want {
+		t.Errorf("GetWebappURL(\"/results\") = %q, want %q", got, want)
+	}
+
+	res, err := http.Get(fake.GetWebappURL("/anything"))
+	if err != nil {
+		t.Fatalf("failed to GET fake server: %v", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusNotFound {
+		t.Errorf("status = %d, want %d (default handler)", res.StatusCode, http.StatusNotFound)
+	}
+}
+
+func TestFakeAppServer_Close(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	fake := NewFakeAppServer(ctrl)
+	server := fake.Server
+	if err := fake.Close(); err != nil {
+		t.Errorf("Close() = %v, want nil", err)
+	}
+
+	if _, err := http.Get(server.URL); err == nil {
+		t.Error("GET succeeded after Close(), want the server to be shut down")
+	}
+}
@@ -0,0 +1,166 @@
+package webdriver
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"time"
+
+	"cloud.google.com/go/datastore"
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/client"
+	"github.com/docker/go-connections/nat"
+)
+
+// dockerImage is the pinned image bundling the Datastore emulator and a
+// compiled instance of the webapp, used by dockerBackend for hermetic,
+// reproducible integration test runs.
+const dockerImage = "gcr.io/wptdashboard/webapp-integration:pinned"
+
+const (
+	dockerAppPort       = nat.Port("8080/tcp")
+	dockerDatastorePort = nat.Port("8081/tcp")
+)
+
+// dockerBackend runs the webapp and its Datastore emulator inside a single
+// container via the Docker Engine API, instead of on the host. This avoids
+// the host needing a working Python App Engine SDK, a recurring source of
+// local and CI flakiness, and gives CI a reproducible, versioned runtime.
+type dockerBackend struct {
+	cli *client.Client
+
+	containerID   string
+	host          string
+	port          int
+	datastorePort int
+
+	startupTimeout time.Duration
+	events         chan StartupEvent
+}
+
+// newDockerBackend prepares (but does not start) a Docker-backed instance of
+// the webapp. startupTimeout bounds how long AwaitReady waits for the
+// container and app port to become reachable.
+func newDockerBackend(startupTimeout time.Duration) (*dockerBackend, error) {
+	cli, err := client.NewClientWithOpts(client.FromEnv, client.WithAPIVersionNegotiation())
+	if err != nil {
+		return nil, fmt.Errorf("failed to create docker client: %v", err)
+	}
+	return &dockerBackend{
+		cli: cli,
+
+		host:          "localhost",
+		port:          pickUnusedPort(),
+		datastorePort: pickUnusedPort(),
+
+		startupTimeout: startupTimeout,
+		events:         make(chan StartupEvent, startupEventsBuffer),
+	}, nil
+}
+
+func (d *dockerBackend) GetWebappURL(path string) string {
+	return fmt.Sprintf("http://%s:%d%s", d.host, d.port, path)
+}
+
+func (d *dockerBackend) Events() <-chan StartupEvent {
+	return d.events
+}
+
+func (d *dockerBackend) AwaitReady() error {
+	return d.AwaitReadyContext(context.Background())
+}
+
+func (d *dockerBackend) AwaitReadyContext(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, d.startupTimeout)
+	defer cancel()
+
+	if err := d.pullImage(ctx); err != nil {
+		emitEvent(d.events, StartupEvent{Kind: StartupEventFailed, Err: err})
+		return err
+	}
+
+	resp, err := d.cli.ContainerCreate(ctx, &container.Config{
+		Image: dockerImage,
+		ExposedPorts: nat.PortSet{
+			dockerAppPort:       {},
+			dockerDatastorePort: {},
+		},
+	}, &container.HostConfig{
+		PortBindings: nat.PortMap{
+			dockerAppPort:       []nat.PortBinding{{HostIP: "127.0.0.1", HostPort: fmt.Sprintf("%d", d.port)}},
+			dockerDatastorePort: []nat.PortBinding{{HostIP: "127.0.0.1", HostPort: fmt.Sprintf("%d", d.datastorePort)}},
+		},
+	}, nil, nil, "")
+	if err != nil {
+		err = fmt.Errorf("failed to create container from %s: %v", dockerImage, err)
+		emitEvent(d.events, StartupEvent{Kind: StartupEventFailed, Err: err})
+		return err
+	}
+	d.containerID = resp.ID
+
+	if err := d.cli.ContainerStart(ctx, d.containerID, types.ContainerStartOptions{}); err != nil {
+		err = fmt.Errorf("failed to start container: %v", err)
+		emitEvent(d.events, StartupEvent{Kind: StartupEventFailed, Err: err})
+		return err
+	}
+	emitEvent(d.events, StartupEvent{Kind: StartupEventStarted})
+
+	if err := pollWithBackoff(ctx, d.GetWebappURL("/")); err != nil {
+		err = fmt.Errorf("app container never became reachable: %v", err)
+		emitEvent(d.events, StartupEvent{Kind: StartupEventFailed, Err: err})
+		return err
+	}
+	emitEvent(d.events, StartupEvent{Kind: StartupEventReady})
+	return nil
+}
+
+// pullImage pulls dockerImage if it isn't already present locally.
+func (d *dockerBackend) pullImage(ctx context.Context) error {
+	if _, _, err := d.cli.ImageInspectWithRaw(ctx, dockerImage); err == nil {
+		return nil
+	}
+
+	out, err := d.cli.ImagePull(ctx, dockerImage, types.ImagePullOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to pull %s: %v", dockerImage, err)
+	}
+	defer out.Close()
+	_, err = io.Copy(ioutil.Discard, out)
+	return err
+}
+
+func (d *dockerBackend) Close() error {
+	if d.containerID == "" {
+		return nil
+	}
+	timeoutSeconds := 15
+	stopErr := d.cli.ContainerStop(context.Background(), d.containerID, container.StopOptions{Timeout: &timeoutSeconds})
+	// Always attempt removal, even if stopping failed, so Close() doesn't
+	// leave a container behind for CI to accumulate.
+	removeErr := d.cli.ContainerRemove(context.Background(), d.containerID, types.ContainerRemoveOptions{Force: true})
+	if stopErr != nil {
+		return stopErr
+	}
+	return removeErr
+}
+
+// NewContext creates a context backed by a real Datastore client talking to
+// the emulator running inside the container, rather than a remote_api HTTP
+// request.
+func (d *dockerBackend) NewContext() (context.Context, error) {
+	ctx := context.Background()
+	// The datastore library only talks to the emulator instead of
+	// production if DATASTORE_EMULATOR_HOST is set in *this* process's
+	// environment, not the container's.
+	if err := os.Setenv("DATASTORE_EMULATOR_HOST", fmt.Sprintf("%s:%d", d.host, d.datastorePort)); err != nil {
+		return nil, fmt.Errorf("failed to point at datastore emulator: %v", err)
+	}
+	client, err := datastore.NewClient(ctx, emulatorProjectID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create datastore client: %v", err)
+	}
+	return context.WithValue(ctx, datastoreClientContextKey{}, client), nil
+}
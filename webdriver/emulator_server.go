@@ -0,0 +1,148 @@
+package webdriver
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"time"
+
+	"cloud.google.com/go/datastore"
+)
+
+// emulatorProjectID is the fake GCP project the Datastore emulator and the
+// app binary agree to talk to. It doesn't need to exist for real; the
+// emulator accepts any project ID.
+const emulatorProjectID = "wptdashboard"
+
+// emulatorBackend runs the webapp as a compiled binary against the
+// standalone Cloud Datastore emulator, rather than shelling out to the
+// deprecated dev_appserver.py sandbox. Readiness is determined by probing
+// the emulator and app ports directly instead of scraping process output.
+type emulatorBackend struct {
+	datastoreCmd *exec.Cmd
+	appCmd       *exec.Cmd
+
+	host          string
+	port          int
+	datastorePort int
+
+	startupTimeout time.Duration
+	events         chan StartupEvent
+}
+
+// newEmulatorBackend prepares (but does not start) an emulator-backed
+// instance of the webapp. startupTimeout bounds how long AwaitReady waits
+// for the emulator and app ports to become reachable.
+func newEmulatorBackend(startupTimeout time.Duration) (*emulatorBackend, error) {
+	e := &emulatorBackend{
+		startupTimeout: startupTimeout,
+		events:         make(chan StartupEvent, startupEventsBuffer),
+
+		host:          "localhost",
+		port:          pickUnusedPort(),
+		datastorePort: pickUnusedPort(),
+	}
+
+	e.datastoreCmd = exec.Command(
+		"gcloud", "beta", "emulators", "datastore", "start",
+		fmt.Sprintf("--host-port=%s:%d", e.host, e.datastorePort),
+		"--no-store-on-disk",
+		"--consistency=1.0",
+	)
+	e.datastoreCmd.Stdout = os.Stdout
+	e.datastoreCmd.Stderr = os.Stderr
+
+	absAppBinaryPath, err := filepath.Abs("../webapp/webapp")
+	if err != nil {
+		panic(err.Error())
+	}
+	e.appCmd = exec.Command(absAppBinaryPath, fmt.Sprintf("--port=%d", e.port))
+	e.appCmd.Env = append(os.Environ(),
+		fmt.Sprintf("DATASTORE_EMULATOR_HOST=%s:%d", e.host, e.datastorePort))
+	e.appCmd.Stdout = os.Stdout
+	e.appCmd.Stderr = os.Stderr
+
+	return e, nil
+}
+
+func (e *emulatorBackend) GetWebappURL(path string) string {
+	return fmt.Sprintf("http://%s:%d%s", e.host, e.port, path)
+}
+
+func (e *emulatorBackend) Close() error {
+	var firstErr error
+	for _, cmd := range []*exec.Cmd{e.appCmd, e.datastoreCmd} {
+		if cmd.Process == nil {
+			continue
+		}
+		if err := cmd.Process.Kill(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+func (e *emulatorBackend) AwaitReady() error {
+	return e.AwaitReadyContext(context.Background())
+}
+
+func (e *emulatorBackend) Events() <-chan StartupEvent {
+	return e.events
+}
+
+func (e *emulatorBackend) AwaitReadyContext(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, e.startupTimeout)
+	defer cancel()
+
+	if err := e.datastoreCmd.Start(); err != nil {
+		err = fmt.Errorf("failed to start datastore emulator: %v", err)
+		emitEvent(e.events, StartupEvent{Kind: StartupEventFailed, Err: err})
+		return err
+	}
+	emitEvent(e.events, StartupEvent{Kind: StartupEventStarted})
+
+	datastoreAddr := fmt.Sprintf("%s:%d", e.host, e.datastorePort)
+	if err := awaitReachableContext(ctx, datastoreAddr); err != nil {
+		err = fmt.Errorf("datastore emulator never became reachable: %v", err)
+		emitEvent(e.events, StartupEvent{Kind: StartupEventFailed, Err: err})
+		return err
+	}
+
+	if err := e.appCmd.Start(); err != nil {
+		err = fmt.Errorf("failed to start app binary: %v", err)
+		emitEvent(e.events, StartupEvent{Kind: StartupEventFailed, Err: err})
+		return err
+	}
+	appAddr := fmt.Sprintf("%s:%d", e.host, e.port)
+	if err := awaitReachableContext(ctx, appAddr); err != nil {
+		err = fmt.Errorf("app binary never became reachable: %v", err)
+		emitEvent(e.events, StartupEvent{Kind: StartupEventFailed, Err: err})
+		return err
+	}
+	emitEvent(e.events, StartupEvent{Kind: StartupEventReady})
+	return nil
+}
+
+// NewContext creates a context backed by a real Datastore client talking to
+// the emulator, rather than a remote_api HTTP request.
+func (e *emulatorBackend) NewContext() (context.Context, error) {
+	ctx := context.Background()
+	// The datastore library only talks to the emulator instead of
+	// production if DATASTORE_EMULATOR_HOST is set in *this* process's
+	// environment; setting it on e.appCmd.Env only affects the app binary's
+	// child process, not us.
+	if err := os.Setenv("DATASTORE_EMULATOR_HOST", fmt.Sprintf("%s:%d", e.host, e.datastorePort)); err != nil {
+		return nil, fmt.Errorf("failed to point at datastore emulator: %v", err)
+	}
+	client, err := datastore.NewClient(ctx, emulatorProjectID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create datastore client: %v", err)
+	}
+	return context.WithValue(ctx, datastoreClientContextKey{}, client), nil
+}
+
+// datastoreClientContextKey is the context key under which emulatorBackend
+// stores its *datastore.Client.
+type datastoreClientContextKey struct{}
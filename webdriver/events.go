@@ -0,0 +1,41 @@
+package webdriver
+
+// StartupEventKind identifies the stage of a DevAppServerInstance's startup
+// lifecycle that a StartupEvent describes.
+type StartupEventKind string
+
+const (
+	// StartupEventStarted is emitted once the backend's process(es) have
+	// been launched, before readiness is confirmed.
+	StartupEventStarted StartupEventKind = "started"
+	// StartupEventAdminReady is emitted once the backend's admin interface
+	// is known (e.g. scraped from dev_appserver's stderr), which is often
+	// well before the backend is fully ready. Seeding can safely start at
+	// this point, in parallel with the rest of startup.
+	StartupEventAdminReady StartupEventKind = "admin_ready"
+	// StartupEventReady is emitted once the backend is confirmed reachable.
+	StartupEventReady StartupEventKind = "ready"
+	// StartupEventFailed is emitted if startup fails or times out. Err
+	// holds the reason.
+	StartupEventFailed StartupEventKind = "failed"
+)
+
+// StartupEvent describes a single step in a DevAppServerInstance's startup
+// lifecycle, surfaced via DevAppServerInstance.Events so that callers (e.g.
+// tests) can assert on lifecycle without parsing logs.
+type StartupEvent struct {
+	Kind StartupEventKind
+	Err  error
+}
+
+// startupEventsBuffer is sized generously enough that emit never blocks on a
+// slow or absent reader for the handful of events a startup emits.
+const startupEventsBuffer = 8
+
+// emitEvent sends evt on c without blocking if nobody is listening.
+func emitEvent(c chan<- StartupEvent, evt StartupEvent) {
+	select {
+	case c <- evt:
+	default:
+	}
+}
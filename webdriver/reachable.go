@@ -0,0 +1,69 @@
+package webdriver
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"time"
+)
+
+// awaitReachableContext blocks until a TCP connection to addr succeeds, ctx
+// is done, or its deadline is exceeded, polling with a short fixed backoff.
+// This mirrors the AwaitReachable helper used by camlistore's dev server
+// harness, and lets backends that don't print a predictable readiness line
+// on stderr (e.g. the Datastore emulator) signal readiness without scraping
+// logs.
+func awaitReachableContext(ctx context.Context, addr string) error {
+	var lastErr error
+	for {
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("timed out waiting for %s to become reachable: %v", addr, lastErr)
+		default:
+		}
+		var d net.Dialer
+		conn, err := d.DialContext(ctx, "tcp", addr)
+		if err == nil {
+			conn.Close()
+			return nil
+		}
+		lastErr = err
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("timed out waiting for %s to become reachable: %v", addr, lastErr)
+		case <-time.After(100 * time.Millisecond):
+		}
+	}
+}
+
+// pollWithBackoff issues unauthenticated GET requests to url, doubling the
+// delay between attempts (up to maxPollBackoff), until it gets any HTTP
+// response or ctx is done. This is used as a readiness fallback for admin
+// endpoints that, unlike camlistore's AwaitReachable targets, need an actual
+// HTTP round trip rather than just a TCP handshake.
+func pollWithBackoff(ctx context.Context, url string) error {
+	const maxPollBackoff = 2 * time.Second
+	backoff := 100 * time.Millisecond
+	var lastErr error
+	for {
+		req, err := http.NewRequest(http.MethodGet, url, nil)
+		if err != nil {
+			return err
+		}
+		res, err := http.DefaultClient.Do(req.WithContext(ctx))
+		if err == nil {
+			res.Body.Close()
+			return nil
+		}
+		lastErr = err
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("timed out polling %s: %v", url, lastErr)
+		case <-time.After(backoff):
+		}
+		if backoff *= 2; backoff > maxPollBackoff {
+			backoff = maxPollBackoff
+		}
+	}
+}
@@ -0,0 +1,62 @@
+package webdriver
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestAwaitReachableContext_alreadyListening(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	defer ln.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if err := awaitReachableContext(ctx, ln.Addr().String()); err != nil {
+		t.Errorf("awaitReachableContext() = %v, want nil", err)
+	}
+}
+
+func TestAwaitReachableContext_timesOut(t *testing.T) {
+	// Nothing is listening on this port.
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	addr := ln.Addr().String()
+	ln.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+	if err := awaitReachableContext(ctx, addr); err == nil {
+		t.Error("awaitReachableContext() = nil, want a timeout error")
+	}
+}
+
+func TestPollWithBackoff_succeedsOnceServerIsUp(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if err := pollWithBackoff(ctx, server.URL); err != nil {
+		t.Errorf("pollWithBackoff() = %v, want nil", err)
+	}
+}
+
+func TestPollWithBackoff_timesOut(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+	// Nothing is listening on this address.
+	if err := pollWithBackoff(ctx, "http://127.0.0.1:1"); err == nil {
+		t.Error("pollWithBackoff() = nil, want a timeout error")
+	}
+}
@@ -0,0 +1,122 @@
+package webdriver
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+)
+
+// SeedProgress reports incremental progress of a Seeder run, streamed over
+// the channel returned by Seeder.Seed so that callers can observe seeding
+// without parsing subprocess output.
+type SeedProgress struct {
+	// Category is the static data category this progress report is for,
+	// e.g. "runs", "metadata", or "users".
+	Category string
+	// Done is true once Category has finished seeding successfully.
+	Done bool
+	// Err is set if seeding Category failed; the channel is closed without
+	// further progress after an error.
+	Err error
+}
+
+// Seeder populates a running backend with the static test data used by
+// integration tests.
+type Seeder interface {
+	// Seed populates the requested categories into app, streaming progress
+	// over the returned channel. The channel is closed once every category
+	// has been seeded, or the first one fails.
+	Seed(ctx context.Context, app AppServer, categories []string) <-chan SeedProgress
+
+	// SeedFromFixture loads a custom run summary fixture from path, keyed
+	// by StaticTestDataRevision, instead of the repo's built-in static
+	// data.
+	SeedFromFixture(ctx context.Context, app AppServer, path string) error
+}
+
+// supportedSeedCategories lists the --seed categories every Seeder
+// implementation currently understands. Keep in sync with
+// subprocessSeeder.Seed and datastoreSeeder.Seed.
+var supportedSeedCategories = map[string]bool{
+	"runs": true,
+}
+
+// validateSeedCategories checks that every requested category is one a
+// Seeder can actually seed, so that an unsupported --seed value fails fast
+// at startup instead of panicking partway through seeding a running
+// backend.
+func validateSeedCategories(categories []string) error {
+	for _, category := range categories {
+		if !supportedSeedCategories[category] {
+			return fmt.Errorf("unsupported --seed category %q (supported: runs)", category)
+		}
+	}
+	return nil
+}
+
+// newSeeder picks the Seeder implementation appropriate for a backend of the
+// given kind: subprocessSeeder for the legacy dev_appserver.py sandbox,
+// which only understands talking to it via remote_api, and datastoreSeeder
+// for the emulator-backed backends, which can write directly.
+func newSeeder(kind backendKind) Seeder {
+	switch kind {
+	case backendEmulator, backendDocker:
+		return datastoreSeeder{}
+	default:
+		return subprocessSeeder{}
+	}
+}
+
+// subprocessSeeder is the original Seeder: it shells out to
+// util/populate_dev_data.go, the same way addStaticData always has.
+type subprocessSeeder struct{}
+
+// Seed runs util/populate_dev_data.go once per category, in order, each as
+// its own subprocess invocation.
+func (subprocessSeeder) Seed(ctx context.Context, app AppServer, categories []string) <-chan SeedProgress {
+	progress := make(chan SeedProgress, len(categories))
+	go func() {
+		defer close(progress)
+		dev, ok := app.(*devAppServerInstance)
+		if !ok {
+			progress <- SeedProgress{Err: fmt.Errorf("subprocess seeder requires the devappserver backend, got %T", app)}
+			return
+		}
+		for _, category := range categories {
+			if err := runPopulateDevData(ctx, dev, category); err != nil {
+				progress <- SeedProgress{Category: category, Err: err}
+				return
+			}
+			progress <- SeedProgress{Category: category, Done: true}
+		}
+	}()
+	return progress
+}
+
+func (subprocessSeeder) SeedFromFixture(ctx context.Context, app AppServer, path string) error {
+	return fmt.Errorf("subprocess seeder cannot load fixtures; use the datastore seeder instead")
+}
+
+// runPopulateDevData shells out to util/populate_dev_data.go to seed a
+// single category against dev's remote_api endpoint.
+func runPopulateDevData(ctx context.Context, dev *devAppServerInstance, category string) error {
+	args := []string{
+		"run",
+		"../util/populate_dev_data.go",
+		fmt.Sprintf("--local_host=localhost:%v", dev.port),
+		fmt.Sprintf("--local_remote_api_host=localhost:%v", dev.apiPort),
+		"--remote_runs=false",
+	}
+	switch category {
+	case "runs":
+		args = append(args, "--static_runs=true")
+	default:
+		return fmt.Errorf("subprocess seeder does not support category %q", category)
+	}
+
+	cmd := exec.CommandContext(ctx, "go", args...)
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	return cmd.Run()
+}
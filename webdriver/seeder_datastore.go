@@ -0,0 +1,134 @@
+package webdriver
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+
+	"cloud.google.com/go/datastore"
+)
+
+// seedRun is the fixture shape accepted by datastoreSeeder: a single test
+// run summary, matching what util/populate_dev_data.go writes for the
+// "runs" category.
+type seedRun struct {
+	Revision       string `json:"revision"`
+	Platform       string `json:"platform"`
+	BrowserVersion string `json:"browser_version"`
+	ResultsURL     string `json:"results_url"`
+}
+
+// datastoreSeederKind is the Datastore entity kind seeded runs are written
+// under, matching the kind the webapp itself queries at this revision.
+const datastoreSeederKind = "TestRun"
+
+// datastoreSeederBatchSize is Datastore's limit on entities per PutMulti
+// call.
+const datastoreSeederBatchSize = 500
+
+// datastoreSeeder is a Seeder that writes directly into the target
+// datastore (typically an emulator) via batched PutMulti calls, avoiding
+// subprocessSeeder's remote_api round trip through a Python subprocess.
+type datastoreSeeder struct{}
+
+func (datastoreSeeder) Seed(ctx context.Context, app AppServer, categories []string) <-chan SeedProgress {
+	progress := make(chan SeedProgress, len(categories))
+	go func() {
+		defer close(progress)
+		client, err := datastoreSeederClient(app)
+		if err != nil {
+			progress <- SeedProgress{Err: err}
+			return
+		}
+		for _, category := range categories {
+			if category != "runs" {
+				progress <- SeedProgress{Category: category, Err: fmt.Errorf("datastore seeder does not support category %q", category)}
+				return
+			}
+			if err := seedStaticRuns(ctx, client); err != nil {
+				progress <- SeedProgress{Category: category, Err: err}
+				return
+			}
+			progress <- SeedProgress{Category: category, Done: true}
+		}
+	}()
+	return progress
+}
+
+func (datastoreSeeder) SeedFromFixture(ctx context.Context, app AppServer, path string) error {
+	client, err := datastoreSeederClient(app)
+	if err != nil {
+		return err
+	}
+	return seedFixture(ctx, client, path)
+}
+
+// datastoreSeederClient extracts the *datastore.Client an emulator-backed
+// DevAppServerInstance built its context around.
+func datastoreSeederClient(app AppServer) (*datastore.Client, error) {
+	dev, ok := app.(DevAppServerInstance)
+	if !ok {
+		return nil, fmt.Errorf("datastore seeder requires a DevAppServerInstance, got %T", app)
+	}
+	dsCtx, err := dev.NewContext()
+	if err != nil {
+		return nil, err
+	}
+	client, ok := dsCtx.Value(datastoreClientContextKey{}).(*datastore.Client)
+	if !ok {
+		return nil, errors.New("datastore seeder requires an emulator-backed context")
+	}
+	return client, nil
+}
+
+// seedStaticRuns seeds the repo's built-in static test run summaries.
+func seedStaticRuns(ctx context.Context, client *datastore.Client) error {
+	absPath, err := filepath.Abs("../util/static_runs.json")
+	if err != nil {
+		return err
+	}
+	return seedFixture(ctx, client, absPath)
+}
+
+// seedFixture reads a JSON array of seedRun fixtures from path and writes
+// them into client in batches, keyed by StaticTestDataRevision rather than
+// auto-generated IDs, so that re-seeding the same fixture overwrites rather
+// than duplicates.
+func seedFixture(ctx context.Context, client *datastore.Client, path string) error {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read fixture %s: %v", path, err)
+	}
+	var runs []seedRun
+	if err := json.Unmarshal(data, &runs); err != nil {
+		return fmt.Errorf("failed to parse fixture %s: %v", path, err)
+	}
+
+	for batchIndex, batch := range batchSeedRuns(runs, datastoreSeederBatchSize) {
+		keys := make([]*datastore.Key, len(batch))
+		for i, run := range batch {
+			keys[i] = datastore.NameKey(datastoreSeederKind, fmt.Sprintf("%s-%s", StaticTestDataRevision, run.Platform), nil)
+		}
+		if _, err := client.PutMulti(ctx, keys, batch); err != nil {
+			return fmt.Errorf("failed to seed %s (batch %d): %v", path, batchIndex, err)
+		}
+	}
+	return nil
+}
+
+// batchSeedRuns splits runs into chunks of at most batchSize, matching
+// Datastore's per-call PutMulti entity limit.
+func batchSeedRuns(runs []seedRun, batchSize int) [][]seedRun {
+	var batches [][]seedRun
+	for start := 0; start < len(runs); start += batchSize {
+		end := start + batchSize
+		if end > len(runs) {
+			end = len(runs)
+		}
+		batches = append(batches, runs[start:end])
+	}
+	return batches
+}
@@ -0,0 +1,73 @@
+package webdriver
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseSeedCategories(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want []string
+	}{
+		{"single", "runs", []string{"runs"}},
+		{"multiple", "runs,metadata,users", []string{"runs", "metadata", "users"}},
+		{"whitespace", " runs ,  metadata", []string{"runs", "metadata"}},
+		{"empty", "", nil},
+		{"trailing comma", "runs,", []string{"runs"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := parseSeedCategories(tt.in)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseSeedCategories(%q) = %v, want %v", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateSeedCategories(t *testing.T) {
+	if err := validateSeedCategories([]string{"runs"}); err != nil {
+		t.Errorf("validateSeedCategories([runs]) = %v, want nil", err)
+	}
+	if err := validateSeedCategories(nil); err != nil {
+		t.Errorf("validateSeedCategories(nil) = %v, want nil", err)
+	}
+	if err := validateSeedCategories([]string{"runs", "metadata"}); err == nil {
+		t.Error("validateSeedCategories([runs, metadata]) = nil, want an error for the unsupported category")
+	}
+}
+
+func TestBatchSeedRuns(t *testing.T) {
+	runs := make([]seedRun, 7)
+	for i := range runs {
+		runs[i] = seedRun{Platform: string(rune('a' + i))}
+	}
+
+	batches := batchSeedRuns(runs, 3)
+	if len(batches) != 3 {
+		t.Fatalf("batchSeedRuns() returned %d batches, want 3", len(batches))
+	}
+	wantSizes := []int{3, 3, 1}
+	for i, batch := range batches {
+		if len(batch) != wantSizes[i] {
+			t.Errorf("batch %d has %d runs, want %d", i, len(batch), wantSizes[i])
+		}
+	}
+
+	// Flattening the batches back out should reproduce the input in order.
+	var flattened []seedRun
+	for _, batch := range batches {
+		flattened = append(flattened, batch...)
+	}
+	if !reflect.DeepEqual(flattened, runs) {
+		t.Errorf("batches did not cover all runs in order: got %v, want %v", flattened, runs)
+	}
+}
+
+func TestBatchSeedRuns_empty(t *testing.T) {
+	if batches := batchSeedRuns(nil, 500); len(batches) != 0 {
+		t.Errorf("batchSeedRuns(nil, 500) = %v, want no batches", batches)
+	}
+}
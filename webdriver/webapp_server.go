@@ -1,5 +1,7 @@
 package webdriver
 
+//go:generate mockgen -destination=../shared/sharedtest/webapp_server_mock.go -package=sharedtest github.com/web-platform-tests/wpt.fyi/webdriver AppServer,DevAppServerInstance
+
 import (
 	"bufio"
 	"context"
@@ -13,19 +15,42 @@ import (
 	"os/exec"
 	"path/filepath"
 	"regexp"
+	"strings"
+	"sync"
 	"time"
 
 	"google.golang.org/appengine/remote_api"
 )
 
 var (
-	staging    = flag.Bool("staging", false, "Use the app's deployed staging instance")
-	remoteHost = flag.String("remote_host", "staging.wpt.fyi", "Remote host of the staging webapp")
+	staging        = flag.Bool("staging", false, "Use the app's deployed staging instance")
+	remoteHost     = flag.String("remote_host", "staging.wpt.fyi", "Remote host of the staging webapp")
+	backend        = flag.String("backend", string(backendDevAppServer), "Backend to run the webapp with: devappserver, emulator, docker, or staging")
+	seed           = flag.String("seed", "runs", `Comma-separated static data categories to seed (currently only "runs" is supported)`)
+	startupTimeout = flag.Duration("startup_timeout", 90*time.Second, "How long to wait for the emulator/docker backend to become ready")
 )
 
 // StaticTestDataRevision is the SHA for the local (static) test run summaries.
 const StaticTestDataRevision = "24278ab61781de72ed363b866ae6b50b86822b27"
 
+// backendKind identifies which local Backend implementation NewWebserver
+// should construct when not talking to a staging instance.
+type backendKind string
+
+const (
+	// backendDevAppServer drives the deprecated Python dev_appserver.py.
+	backendDevAppServer backendKind = "devappserver"
+	// backendEmulator drives the standalone Datastore/Firestore emulator
+	// alongside a compiled instance of the app binary.
+	backendEmulator backendKind = "emulator"
+	// backendDocker drives the webapp and its Datastore emulator inside a
+	// container via the Docker Engine API.
+	backendDocker backendKind = "docker"
+	// backendStaging is an alias for the --staging flag, so that --backend
+	// alone can select the remote staging instance.
+	backendStaging backendKind = "staging"
+)
+
 // AppServer is an abstraction for navigating an instance of the webapp.
 type AppServer interface {
 	// Hook for closing the process that runs the webserver.
@@ -57,26 +82,75 @@ type DevAppServerInstance interface {
 	// said the server is running.
 	AwaitReady() error
 
-	// NewContext creates a context object backed by a remote api HTTP request.
+	// AwaitReadyContext is like AwaitReady, but aborts early if ctx is
+	// cancelled or its deadline is exceeded.
+	AwaitReadyContext(ctx context.Context) error
+
+	// Events returns a channel of StartupEvent emitted over the course of
+	// this instance's startup, so that callers can observe its lifecycle
+	// without parsing logs.
+	Events() <-chan StartupEvent
+
+	// NewContext creates a context object for talking to this instance's
+	// backing store. The legacy devAppServerInstance backs it with a
+	// remote_api HTTP request; the emulator and docker backends back it with
+	// a Datastore client pointed at their emulator instead.
 	NewContext() (context.Context, error)
 }
 
+// Backend is a pluggable local implementation of DevAppServerInstance.
+// NewWebserver selects between implementations (the legacy dev_appserver.py
+// sandbox, an emulator-backed one, or a Docker-backed one) via the
+// --backend flag.
+type Backend interface {
+	DevAppServerInstance
+}
+
 type devAppServerInstance struct {
 	cmd            *exec.Cmd
 	stderr         io.Reader
 	startupTimeout time.Duration
+	events         chan StartupEvent
 
 	host    string
 	port    int
 	apiPort int
 
+	// urlMu guards baseURL and adminURL, which scanStderr writes from one
+	// goroutine while awaitAdminProbe (and anything called after AwaitReady
+	// returns) reads from another.
+	urlMu    sync.Mutex
 	baseURL  *url.URL
 	adminURL *url.URL
 }
 
+func (i *devAppServerInstance) getBaseURL() *url.URL {
+	i.urlMu.Lock()
+	defer i.urlMu.Unlock()
+	return i.baseURL
+}
+
+func (i *devAppServerInstance) setBaseURL(u *url.URL) {
+	i.urlMu.Lock()
+	defer i.urlMu.Unlock()
+	i.baseURL = u
+}
+
+func (i *devAppServerInstance) getAdminURL() *url.URL {
+	i.urlMu.Lock()
+	defer i.urlMu.Unlock()
+	return i.adminURL
+}
+
+func (i *devAppServerInstance) setAdminURL(u *url.URL) {
+	i.urlMu.Lock()
+	defer i.urlMu.Unlock()
+	i.adminURL = u
+}
+
 func (i *devAppServerInstance) GetWebappURL(path string) string {
-	if i.baseURL != nil {
-		return fmt.Sprintf("%s%s", i.baseURL.String(), path)
+	if baseURL := i.getBaseURL(); baseURL != nil {
+		return fmt.Sprintf("%s%s", baseURL.String(), path)
 	}
 	// Local dev server doesn't have HTTPS.
 	return fmt.Sprintf("http://%s:%d%s", i.host, i.port, path)
@@ -89,7 +163,7 @@ func (i *devAppServerInstance) Close() error {
 	}()
 
 	// Call the quit handler on the admin server.
-	res, err := http.Get(i.adminURL.String() + "/quit")
+	res, err := http.Get(i.getAdminURL().String() + "/quit")
 	if err != nil {
 		i.cmd.Process.Kill()
 		return fmt.Errorf("unable to call /quit handler: %v", err)
@@ -109,30 +183,95 @@ func (i *devAppServerInstance) Close() error {
 // NewWebserver creates an AppServer instance, which may be backed by local or
 // remote (staging) servers.
 func NewWebserver() (s AppServer, err error) {
-	if *staging {
+	kind := backendKind(*backend)
+	if *staging || kind == backendStaging {
 		return &remoteAppServer{
 			host: *remoteHost,
 		}, nil
 	}
 
-	app, err := newDevAppServer()
+	categories := parseSeedCategories(*seed)
+	if err = validateSeedCategories(categories); err != nil {
+		return nil, err
+	}
+
+	app, err := newBackend(kind)
 	if err != nil {
 		return app, err
 	}
+
+	// Kick off seeding as soon as the backend's admin interface is known,
+	// rather than waiting for AwaitReady to return, so that seeding and the
+	// remainder of startup happen concurrently. Backends that don't report
+	// their admin interface (emulator, docker) are seeded once ready.
+	seedc := make(chan error, 1)
+	go func() { seedc <- seedOnceAdminReady(app, newSeeder(kind), categories) }()
+
 	if err = app.AwaitReady(); err != nil {
 		panic(err)
 	}
-
-	if err = addStaticData(app); err != nil {
+	if err = <-seedc; err != nil {
 		panic(err)
 	}
 	return app, err
 }
 
+// parseSeedCategories splits the comma-separated --seed flag value into
+// individual categories, e.g. "runs,metadata" -> []string{"runs", "metadata"}.
+func parseSeedCategories(flagValue string) []string {
+	var categories []string
+	for _, category := range strings.Split(flagValue, ",") {
+		if category = strings.TrimSpace(category); category != "" {
+			categories = append(categories, category)
+		}
+	}
+	return categories
+}
+
+// seedOnceAdminReady waits for app's admin interface to become known (or for
+// startup to fail) before seeding it with categories, so that seeding can
+// overlap with the rest of AwaitReady instead of following it sequentially.
+func seedOnceAdminReady(app AppServer, seeder Seeder, categories []string) error {
+	dev, ok := app.(DevAppServerInstance)
+	if !ok {
+		// No lifecycle events to wait on; nothing to overlap with.
+		return nil
+	}
+	for evt := range dev.Events() {
+		if evt.Kind == StartupEventFailed {
+			return nil // AwaitReady will surface the failure.
+		}
+		if evt.Kind == StartupEventAdminReady || evt.Kind == StartupEventReady {
+			break
+		}
+	}
+	for progress := range seeder.Seed(context.Background(), app, categories) {
+		if progress.Err != nil {
+			return fmt.Errorf("failed to seed %q: %v", progress.Category, progress.Err)
+		}
+	}
+	return nil
+}
+
+// newBackend constructs the Backend selected by the --backend flag.
+func newBackend(kind backendKind) (Backend, error) {
+	switch kind {
+	case backendEmulator:
+		return newEmulatorBackend(*startupTimeout)
+	case backendDocker:
+		return newDockerBackend(*startupTimeout)
+	case backendDevAppServer, "":
+		return newDevAppServer()
+	default:
+		return nil, fmt.Errorf("unknown backend %q", kind)
+	}
+}
+
 // newDevAppServer creates a dev appserve instance.
 func newDevAppServer() (s *devAppServerInstance, err error) {
 	s = &devAppServerInstance{
 		startupTimeout: 90 * time.Second,
+		events:         make(chan StartupEvent, startupEventsBuffer),
 
 		host:    "localhost",
 		port:    pickUnusedPort(),
@@ -179,53 +318,109 @@ var adminURLRE = regexp.MustCompile(`Starting admin server at: (\S+)`)
 var readyRE = regexp.MustCompile(`GET /_ah/warmup`)
 
 func (i *devAppServerInstance) AwaitReady() error {
+	return i.AwaitReadyContext(context.Background())
+}
+
+func (i *devAppServerInstance) Events() <-chan StartupEvent {
+	return i.events
+}
+
+// AwaitReadyContext starts the Webserver command and waits until either the
+// scraped stderr output, or a fallback admin-endpoint probe, indicates the
+// server is running. It aborts early if ctx is cancelled or its deadline
+// (capped at i.startupTimeout) is exceeded.
+func (i *devAppServerInstance) AwaitReadyContext(ctx context.Context) error {
 	if err := i.cmd.Start(); err != nil {
+		emitEvent(i.events, StartupEvent{Kind: StartupEventFailed, Err: err})
 		return err
 	}
+	emitEvent(i.events, StartupEvent{Kind: StartupEventStarted})
 
-	// Read stderr until we have read the URLs of the API server and admin interface.
-	errc := make(chan error, 1)
-	go func() {
-		s := bufio.NewScanner(i.stderr)
-		for s.Scan() {
-			if match := readyRE.FindStringSubmatch(s.Text()); match != nil {
-				break
-			}
-			if match := hostRE.FindStringSubmatch(s.Text()); match != nil {
-				u, err := url.Parse(match[1])
-				if err != nil {
-					errc <- fmt.Errorf("failed to parse URL %q: %v", match[1], err)
-					return
-				}
-				i.baseURL = u
-			}
-			if match := adminURLRE.FindStringSubmatch(s.Text()); match != nil {
-				u, err := url.Parse(match[1])
-				if err != nil {
-					errc <- fmt.Errorf("failed to parse URL %q: %v", match[1], err)
-					return
-				}
-				i.adminURL = u
-			}
-		}
-		errc <- s.Err()
-	}()
+	ctx, cancel := context.WithTimeout(ctx, i.startupTimeout)
+	defer cancel()
+
+	// Read stderr for the URLs of the API server and admin interface until
+	// the warmup request shows up, in parallel with a fallback probe in case
+	// the expected log lines never show up (e.g. dev_appserver's output format changed).
+	stderrc := make(chan error, 1)
+	go func() { stderrc <- i.scanStderr() }()
 
+	probec := make(chan error, 1)
+	go func() { probec <- i.awaitAdminProbe(ctx) }()
+
+	var err error
 	select {
-	case <-time.After(i.startupTimeout):
+	case <-ctx.Done():
+		err = ctx.Err()
+	case err = <-stderrc:
+		if err == nil && i.getBaseURL() == nil {
+			err = errors.New("unable to find webserver URL")
+		}
+	case err = <-probec:
+	}
+
+	if err != nil {
 		if p := i.cmd.Process; p != nil {
 			p.Kill()
 		}
-		return errors.New("timeout starting child process")
-	case err := <-errc:
-		if err != nil {
-			return fmt.Errorf("error reading web_server.sh process stderr: %v", err)
+		emitEvent(i.events, StartupEvent{Kind: StartupEventFailed, Err: err})
+		return fmt.Errorf("error awaiting dev_appserver readiness: %v", err)
+	}
+	emitEvent(i.events, StartupEvent{Kind: StartupEventReady})
+	return nil
+}
+
+// scanStderr reads dev_appserver's stderr, recording the URLs of the API
+// server and admin interface as they appear, until it sees the warmup
+// request line or stderr ends.
+func (i *devAppServerInstance) scanStderr() error {
+	s := bufio.NewScanner(i.stderr)
+	for s.Scan() {
+		if match := readyRE.FindStringSubmatch(s.Text()); match != nil {
+			break
+		}
+		if match := hostRE.FindStringSubmatch(s.Text()); match != nil {
+			u, err := url.Parse(match[1])
+			if err != nil {
+				return fmt.Errorf("failed to parse URL %q: %v", match[1], err)
+			}
+			i.setBaseURL(u)
+		}
+		if match := adminURLRE.FindStringSubmatch(s.Text()); match != nil {
+			u, err := url.Parse(match[1])
+			if err != nil {
+				return fmt.Errorf("failed to parse URL %q: %v", match[1], err)
+			}
+			i.setAdminURL(u)
+			emitEvent(i.events, StartupEvent{Kind: StartupEventAdminReady})
+		}
+	}
+	return s.Err()
+}
+
+// adminURLGrace is how long awaitAdminProbe gives scanStderr to find the
+// admin URL before falling back to probing the API port directly.
+const adminURLGrace = 5 * time.Second
+
+// awaitAdminProbe polls the admin interface's unauthenticated "/" endpoint
+// with exponential backoff, as a readiness signal independent of stderr
+// scraping. If scanStderr hasn't found an admin URL within adminURLGrace, it
+// falls back to probing localhost:apiPort directly.
+func (i *devAppServerInstance) awaitAdminProbe(ctx context.Context) error {
+	deadline := time.Now().Add(adminURLGrace)
+	for i.getAdminURL() == nil && time.Now().Before(deadline) {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(50 * time.Millisecond):
 		}
 	}
-	if i.baseURL == nil {
-		return errors.New("unable to find webserver URL")
+
+	addr := fmt.Sprintf("http://localhost:%d/", i.apiPort)
+	if adminURL := i.getAdminURL(); adminURL != nil {
+		addr = adminURL.String() + "/"
 	}
-	return nil
+	return pollWithBackoff(ctx, addr)
 }
 
 func (i *devAppServerInstance) NewContext() (ctx context.Context, err error) {
@@ -234,21 +429,3 @@ func (i *devAppServerInstance) NewContext() (ctx context.Context, err error) {
 	remoteContext, err := remote_api.NewRemoteContext(host, http.DefaultClient)
 	return remoteContext, err
 }
-
-func addStaticData(i *devAppServerInstance) (err error) {
-	cmd := exec.Command(
-		"go",
-		"run",
-		"../util/populate_dev_data.go",
-		fmt.Sprintf("--local_host=localhost:%v", i.port),
-		fmt.Sprintf("--local_remote_api_host=localhost:%v", i.apiPort),
-		"--remote_runs=false",
-		"--static_runs=true",
-	)
-	cmd.Stdout = os.Stdout
-	cmd.Stderr = os.Stderr
-	if err = cmd.Start(); err != nil {
-		return err
-	}
-	return cmd.Wait()
-}